@@ -0,0 +1,18 @@
+package main
+
+import (
+	"net/http"
+	"time"
+)
+
+// Shared across every provider call instead of the per-call http.Post the
+// original version used, which reused http.DefaultClient, opened a fresh
+// connection each time, and couldn't be cancelled.
+var httpClient = &http.Client{
+	Transport: &http.Transport{
+		MaxIdleConnsPerHost: 20,
+	},
+}
+
+// Used when the caller doesn't set timeout_seconds on CompletionRequest
+const defaultRequestTimeout = 120 * time.Second
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Connection details for one backend, loaded from providers.json
+type ProviderConfig struct {
+	BaseURL      string `json:"base_url"`
+	APIKey       string `json:"api_key"`
+	DefaultModel string `json:"default_model"`
+}
+
+// Top-level shape of providers.json - one entry per provider name ("ollama",
+// "openai", "anthropic", "google")
+type Config struct {
+	Providers map[string]ProviderConfig `json:"providers"`
+}
+
+// Used when a request doesn't set `provider`
+const defaultProvider = "ollama"
+
+// Set once in main() from loadConfig and read by resolveProvider on every request
+var providerConfig Config
+
+// loadConfig reads the provider routing table from path. If the file doesn't
+// exist, fall back to a single Ollama entry pointing at the default local port
+// so the server still runs with zero configuration.
+func loadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Config{Providers: map[string]ProviderConfig{
+				defaultProvider: {BaseURL: "http://localhost:11434"},
+			}}, nil
+		}
+		return Config{}, fmt.Errorf("failed to read provider config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to parse provider config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// resolveProvider picks the Provider implementation for a request - an
+// explicit `provider` field wins, otherwise it falls back to defaultProvider
+func resolveProvider(cfg Config, name string) (Provider, error) {
+	if name == "" {
+		name = defaultProvider
+	}
+
+	pc, ok := cfg.Providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q", name)
+	}
+
+	switch name {
+	case "ollama":
+		return &OllamaProvider{cfg: pc}, nil
+	case "openai":
+		return &OpenAIProvider{cfg: pc}, nil
+	case "anthropic":
+		return &AnthropicProvider{cfg: pc}, nil
+	case "google":
+		return &GoogleProvider{cfg: pc}, nil
+	default:
+		return nil, fmt.Errorf("unsupported provider %q", name)
+	}
+}
+
+// providerLabel is the name used for logging - falls back to defaultProvider
+// the same way resolveProvider does
+func providerLabel(name string) string {
+	if name == "" {
+		return defaultProvider
+	}
+	return name
+}
@@ -2,9 +2,9 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log"
 	"net/http"
 	"time"
@@ -16,6 +16,51 @@ type CompletionRequest struct {
 	Model     string `json:"model"`
 	Prompt    string `json:"prompt"`
 	MaxTokens int    `json:"max_tokens"`
+	// Which backend to route to - "ollama", "openai", "anthropic", "google".
+	// Empty means defaultProvider
+	Provider string `json:"provider,omitempty"`
+
+	// Multi-turn history, for requests that came in through
+	// /v1/chat/completions. Empty for a plain /v1/completions prompt - see
+	// completionMessages below, which reconciles the two.
+	Messages []Message `json:"messages,omitempty"`
+
+	// Sampling parameters, all optional. Pointers + omitempty so an unset
+	// field is left out of the request entirely rather than forwarded as a
+	// zero value, letting the provider's own defaults apply.
+	Temperature   *float64 `json:"temperature,omitempty"`
+	TopP          *float64 `json:"top_p,omitempty"`
+	TopK          *int     `json:"top_k,omitempty"`
+	Mirostat      *int     `json:"mirostat,omitempty"`
+	MirostatEta   *float64 `json:"mirostat_eta,omitempty"`
+	MirostatTau   *float64 `json:"mirostat_tau,omitempty"`
+	NumCtx        *int     `json:"num_ctx,omitempty"`
+	RepeatPenalty *float64 `json:"repeat_penalty,omitempty"`
+	Seed          *int     `json:"seed,omitempty"`
+	Stop          []string `json:"stop,omitempty"`
+
+	// Per-request timeout override, in seconds. Falls back to
+	// defaultRequestTimeout when unset.
+	TimeoutSeconds *int `json:"timeout_seconds,omitempty"`
+}
+
+// requestTimeout returns the caller's timeout_seconds if set, else defaultRequestTimeout
+func requestTimeout(req CompletionRequest) time.Duration {
+	if req.TimeoutSeconds != nil {
+		return time.Duration(*req.TimeoutSeconds) * time.Second
+	}
+	return defaultRequestTimeout
+}
+
+// completionMessages reconciles the two shapes CompletionRequest can carry a
+// prompt in: a full message history (from /v1/chat/completions) or a single
+// flattened prompt string (from /v1/completions). Providers only need to
+// look at this, not req.Prompt/req.Messages directly.
+func completionMessages(req CompletionRequest) []Message {
+	if len(req.Messages) > 0 {
+		return req.Messages
+	}
+	return []Message{{Role: "user", Content: req.Prompt}}
 }
 
 // Response back to the React frontend
@@ -29,25 +74,170 @@ type Choice struct {
 }
 
 type Usage struct {
-	CompletionTokens int `json:"completion_tokens"`
+	PromptTokens     int      `json:"prompt_tokens"`
+	CompletionTokens int      `json:"completion_tokens"`
+	TotalTokens      int      `json:"total_tokens"`
+	Timings          *Timings `json:"timings,omitempty"`
+}
+
+// Generation speed, derived from the provider's own reported durations -
+// only populated when the provider gives us enough to compute it
+type Timings struct {
+	TokensPerSecond float64 `json:"tokens_per_second"`
 }
 
 // Using Ollama as the inference engine. This is the request format that it uses.
 // It exposes a local API on port 11434
 type OllamaRequest struct {
-	Model  string `json:"model"`
-	Prompt string `json:"prompt"`
-	Stream bool   `json:"stream"`
+	Model   string                 `json:"model"`
+	Prompt  string                 `json:"prompt"`
+	Stream  bool                   `json:"stream"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// Builds Ollama's nested "options" object from whichever sampling parameters
+// the caller actually set - only the keys Ollama's /api/generate recognizes
+// are forwarded
+func samplingOptions(req CompletionRequest) map[string]interface{} {
+	options := map[string]interface{}{}
+
+	if req.Temperature != nil {
+		options["temperature"] = *req.Temperature
+	}
+	if req.TopP != nil {
+		options["top_p"] = *req.TopP
+	}
+	if req.TopK != nil {
+		options["top_k"] = *req.TopK
+	}
+	if req.Mirostat != nil {
+		options["mirostat"] = *req.Mirostat
+	}
+	if req.MirostatEta != nil {
+		options["mirostat_eta"] = *req.MirostatEta
+	}
+	if req.MirostatTau != nil {
+		options["mirostat_tau"] = *req.MirostatTau
+	}
+	if req.NumCtx != nil {
+		options["num_ctx"] = *req.NumCtx
+	}
+	if req.RepeatPenalty != nil {
+		options["repeat_penalty"] = *req.RepeatPenalty
+	}
+	if req.Seed != nil {
+		options["seed"] = *req.Seed
+	}
+	if len(req.Stop) > 0 {
+		options["stop"] = req.Stop
+	}
+
+	if len(options) == 0 {
+		return nil
+	}
+	return options
 }
 
 // Ollama API response format
 // Not all of these fields are used but this is the full response format you get from Ollama
 type OllamaResponse struct {
-	Model         string `json:"model"`
-	Response      string `json:"response"`
-	Done          bool   `json:"done"`
-	TotalDuration int64  `json:"total_duration"`
-	EvalCount     int    `json:"eval_count"`
+	Model              string `json:"model"`
+	Response           string `json:"response"`
+	Done               bool   `json:"done"`
+	TotalDuration      int64  `json:"total_duration"`
+	LoadDuration       int64  `json:"load_duration"`
+	PromptEvalCount    int    `json:"prompt_eval_count"`
+	PromptEvalDuration int64  `json:"prompt_eval_duration"`
+	EvalCount          int    `json:"eval_count"`
+	EvalDuration       int64  `json:"eval_duration"`
+}
+
+// A single turn in a conversation. Role is one of "system", "user", "assistant"
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request from the React frontend for multi-turn chat, as opposed to a single
+// flattened prompt
+type ChatRequest struct {
+	Model     string    `json:"model"`
+	Messages  []Message `json:"messages"`
+	MaxTokens int       `json:"max_tokens"`
+	Stream    bool      `json:"stream"`
+	// Which backend to route to, same meaning as CompletionRequest.Provider
+	Provider string `json:"provider,omitempty"`
+}
+
+// Ollama's /api/chat request format - same idea as OllamaRequest but takes the
+// full message history instead of one flattened prompt string
+type OllamaChatRequest struct {
+	Model    string    `json:"model"`
+	Messages []Message `json:"messages"`
+	Stream   bool      `json:"stream"`
+}
+
+// Ollama's /api/chat response format - the generated turn comes back as a
+// Message rather than a flat Response string
+type OllamaChatResponse struct {
+	Model              string  `json:"model"`
+	Message            Message `json:"message"`
+	Done               bool    `json:"done"`
+	PromptEvalCount    int     `json:"prompt_eval_count"`
+	PromptEvalDuration int64   `json:"prompt_eval_duration"`
+	EvalCount          int     `json:"eval_count"`
+	EvalDuration       int64   `json:"eval_duration"`
+}
+
+// Request from the React frontend for /v1/embeddings. Input is raw JSON because
+// the OpenAI-compatible shape allows either a single string or a batch of
+// strings - it gets normalized by decodeEmbeddingsInput below
+type EmbeddingsRequest struct {
+	Model string          `json:"model"`
+	Input json.RawMessage `json:"input"`
+	// Which backend to route to, same meaning as CompletionRequest.Provider
+	Provider string `json:"provider,omitempty"`
+}
+
+// Ollama's /api/embeddings request format - one prompt per call, no batching
+type OllamaEmbeddingsRequest struct {
+	Model  string `json:"model"`
+	Prompt string `json:"prompt"`
+}
+
+// Ollama's /api/embeddings response format
+type OllamaEmbeddingsResponse struct {
+	Embedding []float64 `json:"embedding"`
+}
+
+// One embedding in an OpenAI-compatible response, tagged with its position in
+// the original input batch
+type EmbeddingData struct {
+	Embedding []float64 `json:"embedding"`
+	Index     int       `json:"index"`
+}
+
+// Response back to the React frontend, OpenAI-compatible shape
+type EmbeddingsResponse struct {
+	Data  []EmbeddingData `json:"data"`
+	Model string          `json:"model"`
+	Usage Usage           `json:"usage"`
+}
+
+// Input accepts either a single string or a batch of strings - normalize both
+// shapes into a slice so the handler always loops the same way
+func decodeEmbeddingsInput(raw json.RawMessage) ([]string, error) {
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}, nil
+	}
+
+	var batch []string
+	if err := json.Unmarshal(raw, &batch); err == nil {
+		return batch, nil
+	}
+
+	return nil, fmt.Errorf("input must be a string or an array of strings")
 }
 
 // ---------------------------------------------------------------------- //
@@ -59,7 +249,7 @@ type OllamaResponse struct {
 func enableCORS(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
 
 		if r.Method == "OPTIONS" {
@@ -92,74 +282,251 @@ func handleCompletion(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Build Ollama request
-	// creates a new struct and assigns the fields from the respective fields in req
-	ollamaReq := OllamaRequest{
-		Model:  req.Model,
-		Prompt: req.Prompt,
-		Stream: false,
-	}
+	serveCompletion(w, r, req)
+}
 
-	// Converts ollamaReq struct into JSON bytes
-	ollamaBody, err := json.Marshal(ollamaReq)
+// serveCompletion resolves req.Provider, runs the completion, and writes the
+// JSON response - shared by handleCompletion and handleChatCompletion (the
+// latter builds req from a ChatRequest first) so both paths go through the
+// same Provider abstraction.
+func serveCompletion(w http.ResponseWriter, r *http.Request, req CompletionRequest) {
+	// req.Provider picks which backend handles this request - see provider.go
+	provider, err := resolveProvider(providerConfig, req.Provider)
 	if err != nil {
-		http.Error(w, "Failed to build request", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Send to Ollama
+	// Cancelled automatically if the frontend aborts the fetch, and bounded
+	// by timeout_seconds (or defaultRequestTimeout) so a stuck provider call
+	// can't hang a connection forever
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout(req))
+	defer cancel()
+
 	// Starts timer for request to model. This is not for the latency metric which is calculated by the frontend it is for logging server side latency
 	start := time.Now()
-	// Posts ollamabody to ollama inference engine. The response back from ollama gets stored in resp. The 'model' field in the JSON body tells ollama which model to use.
-	resp, err := http.Post("http://localhost:11434/api/generate", "application/json", bytes.NewBuffer(ollamaBody))
+	response, err := provider.Complete(ctx, req)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Ollama error: %v", err), http.StatusBadGateway)
+		http.Error(w, fmt.Sprintf("Provider error: %v", err), http.StatusBadGateway)
 		return
 	}
 
-	// Do this later once the function finishes execution
-	// Response Body is an open connection to Ollama which is a strean you read data from. You need to close it.
-	defer resp.Body.Close()
+	// Ends timer and stores result in elapsed
+	elapsed := time.Since(start)
+
+	log.Printf("Model: %s | Provider: %s | Latency: %v | Tokens: %d", req.Model, providerLabel(req.Provider), elapsed, response.Usage.CompletionTokens)
+
+	// sets the header telling the frontend this is json
+	w.Header().Set("Content-Type", "application/json")
+	// sends w as the reponse back to the frontend
+	json.NewEncoder(w).Encode(response)
+}
+
+// Same as handleCompletion but asks the provider to stream its response
+// token-by-token instead of waiting for the whole generation to finish, and
+// relays each chunk to the frontend over Server-Sent Events as it arrives.
+func handleCompletionStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Not a POST request - Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
 
-	// Read Ollama response
-	// io.ReadAll reads the stream in a slice of bytes which is stored in body
-	body, err := io.ReadAll(resp.Body)
+	var req CompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	serveCompletionStream(w, r, req)
+}
+
+// serveCompletionStream is the streaming counterpart of serveCompletion,
+// shared the same way with handleChatCompletion.
+func serveCompletionStream(w http.ResponseWriter, r *http.Request, req CompletionRequest) {
+	provider, err := resolveProvider(providerConfig, req.Provider)
 	if err != nil {
-		http.Error(w, "Failed to read response", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// create a new OllamaResponse struct and parse body into the struct mapping the keys to the struct tags
-	var ollamaResp OllamaResponse
-	if err := json.Unmarshal(body, &ollamaResp); err != nil {
-		http.Error(w, "Failed to parse Ollama response", http.StatusInternalServerError)
+	// Frontend needs to know this is an event stream, not a normal JSON response
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// http.ResponseWriter only flushes to the client on this interface - without
+	// it the chunks would sit buffered until the handler returns
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
 		return
 	}
 
-	// Ends timer and stores result in elapsed
+	ctx, cancel := context.WithTimeout(r.Context(), requestTimeout(req))
+	defer cancel()
+
+	start := time.Now()
+
+	// Provider.Stream runs on its own goroutine and closes chunks when done -
+	// the handler just drains it and forwards each chunk to the client
+	chunks := make(chan Chunk)
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- provider.Stream(ctx, req, chunks)
+	}()
+
+	tokens := 0
+	var usage *Usage
+	for chunk := range chunks {
+		if chunk.Done {
+			usage = chunk.Usage
+			break
+		}
+
+		tokens += len(bytes.Fields([]byte(chunk.Text)))
+
+		data, _ := json.Marshal(chunk)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+
+	if err := <-streamErr; err != nil {
+		log.Printf("Provider stream error: %v", err)
+	}
+
 	elapsed := time.Since(start)
 
-	// Estimate tokens (rough: split by spaces)
-	tokens := len(bytes.Fields([]byte(ollamaResp.Response)))
+	// Prefer the provider's own reported counts (set on the final chunk) over
+	// the whitespace estimate, the same way serveCompletion prefers
+	// provider.Complete's Usage over guessing
+	completionTokens := tokens
+	var promptTokens int
+	var tokensPerSecond float64
+	if usage != nil {
+		completionTokens = usage.CompletionTokens
+		promptTokens = usage.PromptTokens
+		if usage.Timings != nil {
+			tokensPerSecond = usage.Timings.TokensPerSecond
+		}
+	}
+
+	done := struct {
+		Tokens          int     `json:"tokens"`
+		PromptTokens    int     `json:"prompt_tokens,omitempty"`
+		TokensPerSecond float64 `json:"tokens_per_second,omitempty"`
+		Elapsed         float64 `json:"elapsed_seconds"`
+	}{Tokens: completionTokens, PromptTokens: promptTokens, TokensPerSecond: tokensPerSecond, Elapsed: elapsed.Seconds()}
 
-	// Build response for frontend
-	response := CompletionResponse{
-		Choices: []Choice{{Text: ollamaResp.Response}},
-		Usage:   Usage{CompletionTokens: tokens},
+	doneData, _ := json.Marshal(done)
+	fmt.Fprintf(w, "event: done\ndata: %s\n\n", doneData)
+	flusher.Flush()
+
+	log.Printf("Model: %s | Provider: %s | Latency: %v | Tokens: %d (streamed)", req.Model, providerLabel(req.Provider), elapsed, completionTokens)
+}
+
+// Like handleCompletion but for multi-turn conversations: the full message
+// history is forwarded on every call so the model has the prior turns as
+// context, instead of the frontend flattening everything into one prompt
+// string. Goes through the same Provider abstraction as handleCompletion -
+// req.Stream picks serveCompletionStream over serveCompletion the same way
+// /v1/completions/stream does for plain prompts.
+func handleChatCompletion(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Not a POST request - Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ChatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
 	}
 
-	log.Printf("Model: %s | Latency: %v | Tokens: %d", req.Model, elapsed, tokens)
+	completionReq := CompletionRequest{
+		Model:     req.Model,
+		Messages:  req.Messages,
+		MaxTokens: req.MaxTokens,
+		Provider:  req.Provider,
+	}
+
+	if req.Stream {
+		serveCompletionStream(w, r, completionReq)
+		return
+	}
+	serveCompletion(w, r, completionReq)
+}
+
+// Turns text into vector embeddings via whichever provider's Embed method
+// req.Provider resolves to - see Embedder in provider.go
+func handleEmbeddings(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Not a POST request - Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req EmbeddingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	inputs, err := decodeEmbeddingsInput(req.Input)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	provider, err := resolveProvider(providerConfig, req.Provider)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	embedder, ok := provider.(Embedder)
+	if !ok {
+		http.Error(w, fmt.Sprintf("provider %s does not support embeddings", providerLabel(req.Provider)), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), defaultRequestTimeout)
+	defer cancel()
+
+	data, usage, err := embedder.Embed(ctx, req.Model, inputs)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Provider error: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	response := EmbeddingsResponse{
+		Data:  data,
+		Model: req.Model,
+		Usage: usage,
+	}
+
+	log.Printf("Model: %s | Provider: %s | Embeddings: %d", req.Model, providerLabel(req.Provider), len(data))
 
-	// sets the header telling the frontend this is json
 	w.Header().Set("Content-Type", "application/json")
-	// sends w as the reponse back to the frontend
 	json.NewEncoder(w).Encode(response)
 }
 
 func main() {
+	// Load the provider routing table (base URL, API key, default model per
+	// provider). Falls back to a single local Ollama entry if no config file
+	// is present - see config.go
+	cfg, err := loadConfig("providers.json")
+	if err != nil {
+		log.Fatalf("failed to load provider config: %v", err)
+	}
+	providerConfig = cfg
+
 	// http.HandleFunc - built into Go std lib. (not the same as http.HanderFunc) Imported with net/http. This line means, when someone sends a request to '/v1/completions' run 'handleCompletion'
 	// Frontend hits backend on this line
 	http.HandleFunc("/v1/completions", enableCORS(handleCompletion))
+	http.HandleFunc("/v1/completions/stream", enableCORS(handleCompletionStream))
+	http.HandleFunc("/v1/chat/completions", enableCORS(handleChatCompletion))
+	http.HandleFunc("/v1/embeddings", enableCORS(handleEmbeddings))
+	http.HandleFunc("/v1/models", enableCORS(handleModels))
+	http.HandleFunc("/v1/models/pull", enableCORS(handleModelsPull))
+	http.HandleFunc("/v1/models/", enableCORS(handleModelByName))
 
 	fmt.Println("Backend running on http://localhost:8000")
 
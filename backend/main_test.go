@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		req  CompletionRequest
+		want time.Duration
+	}{
+		{name: "unset falls back to default", req: CompletionRequest{}, want: defaultRequestTimeout},
+		{name: "override in seconds", req: CompletionRequest{TimeoutSeconds: ptr(30)}, want: 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := requestTimeout(tt.req); got != tt.want {
+				t.Errorf("requestTimeout(%+v) = %v, want %v", tt.req, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSamplingOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		req  CompletionRequest
+		want map[string]interface{}
+	}{
+		{name: "nothing set", req: CompletionRequest{}, want: nil},
+		{
+			name: "temperature only",
+			req:  CompletionRequest{Temperature: ptr(0.7)},
+			want: map[string]interface{}{"temperature": 0.7},
+		},
+		{
+			name: "stop words only set when non-empty",
+			req:  CompletionRequest{Stop: []string{"\n"}},
+			want: map[string]interface{}{"stop": []string{"\n"}},
+		},
+		{
+			name: "every field set",
+			req: CompletionRequest{
+				Temperature:   ptr(0.5),
+				TopP:          ptr(0.9),
+				TopK:          ptr(40),
+				Mirostat:      ptr(2),
+				MirostatEta:   ptr(0.1),
+				MirostatTau:   ptr(5.0),
+				NumCtx:        ptr(4096),
+				RepeatPenalty: ptr(1.1),
+				Seed:          ptr(42),
+				Stop:          []string{"###"},
+			},
+			want: map[string]interface{}{
+				"temperature":    0.5,
+				"top_p":          0.9,
+				"top_k":          40,
+				"mirostat":       2,
+				"mirostat_eta":   0.1,
+				"mirostat_tau":   5.0,
+				"num_ctx":        4096,
+				"repeat_penalty": 1.1,
+				"seed":           42,
+				"stop":           []string{"###"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := samplingOptions(tt.req)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("samplingOptions(%+v) = %v, want %v", tt.req, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeEmbeddingsInput(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		want    []string
+		wantErr bool
+	}{
+		{name: "single string", raw: `"hello world"`, want: []string{"hello world"}},
+		{name: "batch of strings", raw: `["hello", "world"]`, want: []string{"hello", "world"}},
+		{name: "empty batch", raw: `[]`, want: []string{}},
+		{name: "neither string nor array", raw: `42`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeEmbeddingsInput(json.RawMessage(tt.raw))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("decodeEmbeddingsInput(%s) = %v, want error", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeEmbeddingsInput(%s) returned error: %v", tt.raw, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeEmbeddingsInput(%s) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
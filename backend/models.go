@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Ollama's /api/tags response - one entry per locally pulled model
+type OllamaTagsResponse struct {
+	Models []OllamaModelInfo `json:"models"`
+}
+
+type OllamaModelInfo struct {
+	Name       string `json:"name"`
+	Size       int64  `json:"size"`
+	ModifiedAt string `json:"modified_at"`
+}
+
+// One model in the OpenAI-compatible /v1/models response
+type ModelInfo struct {
+	ID         string `json:"id"`
+	Size       int64  `json:"size"`
+	ModifiedAt string `json:"modified_at"`
+}
+
+type ModelsResponse struct {
+	Data []ModelInfo `json:"data"`
+}
+
+// Ollama's /api/pull request - Stream defaults to true server-side, but it's
+// set explicitly here so progress comes back as newline-delimited JSON
+type OllamaPullRequest struct {
+	Name   string `json:"name"`
+	Stream bool   `json:"stream"`
+}
+
+// One line of progress from Ollama's /api/pull - Completed/Total are only
+// present once the layer being pulled reports a size
+type OllamaPullProgress struct {
+	Status    string `json:"status"`
+	Completed int64  `json:"completed,omitempty"`
+	Total     int64  `json:"total,omitempty"`
+}
+
+// Ollama's /api/delete request
+type OllamaDeleteRequest struct {
+	Name string `json:"name"`
+}
+
+// Request from the React frontend for POST /v1/models/pull
+type ModelPullRequest struct {
+	Name string `json:"name"`
+}
+
+// ollamaBaseURL returns the configured Ollama base URL, falling back to the
+// default local port if no "ollama" entry is configured
+func ollamaBaseURL() string {
+	if pc, ok := providerConfig.Providers["ollama"]; ok && pc.BaseURL != "" {
+		return pc.BaseURL
+	}
+	return "http://localhost:11434"
+}
+
+// GET /v1/models - proxies Ollama's /api/tags so the frontend can list
+// locally available models
+func handleModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Not a GET request - Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	httpReq, err := http.NewRequestWithContext(r.Context(), http.MethodGet, ollamaBaseURL()+"/api/tags", nil)
+	if err != nil {
+		http.Error(w, "Failed to build request", http.StatusInternalServerError)
+		return
+	}
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Ollama error: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		http.Error(w, "Failed to read response", http.StatusInternalServerError)
+		return
+	}
+
+	var tags OllamaTagsResponse
+	if err := json.Unmarshal(body, &tags); err != nil {
+		http.Error(w, "Failed to parse Ollama response", http.StatusInternalServerError)
+		return
+	}
+
+	data := make([]ModelInfo, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		data = append(data, ModelInfo{ID: m.Name, Size: m.Size, ModifiedAt: m.ModifiedAt})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ModelsResponse{Data: data})
+}
+
+// POST /v1/models/pull {name} - proxies Ollama's /api/pull and relays its
+// newline-delimited progress updates to the frontend as SSE
+func handleModelsPull(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Not a POST request - Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req ModelPullRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	pullBody, err := json.Marshal(OllamaPullRequest{Name: req.Name, Stream: true})
+	if err != nil {
+		http.Error(w, "Failed to build request", http.StatusInternalServerError)
+		return
+	}
+
+	httpReq, err := http.NewRequestWithContext(r.Context(), http.MethodPost, ollamaBaseURL()+"/api/pull", bytes.NewBuffer(pullBody))
+	if err != nil {
+		http.Error(w, "Failed to build request", http.StatusInternalServerError)
+		return
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Ollama error: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var progress OllamaPullProgress
+		if err := json.Unmarshal(scanner.Bytes(), &progress); err != nil {
+			continue
+		}
+
+		data, _ := json.Marshal(progress)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+
+	fmt.Fprintf(w, "event: done\ndata: {}\n\n")
+	flusher.Flush()
+
+	log.Printf("Pulled model: %s", req.Name)
+}
+
+// DELETE /v1/models/{name} - proxies Ollama's /api/delete
+func handleModelByName(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "Not a DELETE request - Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, "/v1/models/")
+	if name == "" {
+		http.Error(w, "Model name required", http.StatusBadRequest)
+		return
+	}
+
+	deleteBody, err := json.Marshal(OllamaDeleteRequest{Name: name})
+	if err != nil {
+		http.Error(w, "Failed to build request", http.StatusInternalServerError)
+		return
+	}
+
+	httpReq, err := http.NewRequestWithContext(r.Context(), http.MethodDelete, ollamaBaseURL()+"/api/delete", bytes.NewBuffer(deleteBody))
+	if err != nil {
+		http.Error(w, "Failed to build request", http.StatusInternalServerError)
+		return
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Ollama error: %v", err), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		http.Error(w, fmt.Sprintf("Ollama error: %s", body), http.StatusBadGateway)
+		return
+	}
+
+	log.Printf("Deleted model: %s", name)
+	w.WriteHeader(http.StatusNoContent)
+}
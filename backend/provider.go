@@ -0,0 +1,34 @@
+package main
+
+import "context"
+
+// One incremental piece of a streamed completion. Done is set on the final
+// value sent on the channel and Text is empty in that case. Usage is only
+// populated on the final value, and only by providers that report real token
+// counts for streamed responses (currently Ollama) - nil means the caller
+// should fall back to its own estimate.
+type Chunk struct {
+	Text  string `json:"text"`
+	Done  bool   `json:"done"`
+	Usage *Usage `json:"usage,omitempty"`
+}
+
+// Provider abstracts over a backend inference API so the handlers in main.go
+// don't need to know whether a request is headed to Ollama, OpenAI,
+// Anthropic, or Gemini. Each implementation translates CompletionRequest into
+// its own native request/response shape and normalizes errors.
+type Provider interface {
+	Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error)
+	// Stream sends one Chunk per incremental piece of output and closes
+	// chunks when the response is finished (or the call fails)
+	Stream(ctx context.Context, req CompletionRequest, chunks chan<- Chunk) error
+}
+
+// Embedder is implemented by providers that can turn text into vector
+// embeddings. It's separate from Provider rather than folded into it
+// because not every provider has an embeddings API (Anthropic doesn't, for
+// one) - handleEmbeddings type-asserts for this instead of requiring every
+// Provider to implement it.
+type Embedder interface {
+	Embed(ctx context.Context, model string, inputs []string) ([]EmbeddingData, Usage, error)
+}
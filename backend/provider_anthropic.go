@@ -0,0 +1,189 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Anthropic's Messages API request/response shapes (only the fields this
+// proxy needs)
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicMessagesRequest struct {
+	Model     string             `json:"model"`
+	MaxTokens int                `json:"max_tokens"`
+	Messages  []anthropicMessage `json:"messages"`
+	Stream    bool               `json:"stream"`
+}
+
+type anthropicMessagesResponse struct {
+	Content []struct {
+		Text string `json:"text"`
+	} `json:"content"`
+	Usage struct {
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// Event shape from Anthropic's streaming Messages API. Only the fields this
+// proxy cares about are parsed - content_block_delta carries text, message_stop
+// marks the end of the response.
+type anthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Text string `json:"text"`
+	} `json:"delta"`
+}
+
+type AnthropicProvider struct {
+	cfg ProviderConfig
+}
+
+func (p *AnthropicProvider) baseURL() string {
+	if p.cfg.BaseURL != "" {
+		return p.cfg.BaseURL
+	}
+	return "https://api.anthropic.com/v1"
+}
+
+func (p *AnthropicProvider) model(req CompletionRequest) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	return p.cfg.DefaultModel
+}
+
+func (p *AnthropicProvider) maxTokens(req CompletionRequest) int {
+	if req.MaxTokens > 0 {
+		return req.MaxTokens
+	}
+	return 1024
+}
+
+// anthropicMessages converts completionMessages(req) into Anthropic's
+// message shape. Anthropic takes system prompts via a separate top-level
+// field rather than a "system" role message, but this proxy doesn't send
+// one today - a "system" role turn is forwarded as-is, same as any other.
+func anthropicMessages(req CompletionRequest) []anthropicMessage {
+	messages := completionMessages(req)
+	anthropicMsgs := make([]anthropicMessage, len(messages))
+	for i, m := range messages {
+		anthropicMsgs[i] = anthropicMessage{Role: m.Role, Content: m.Content}
+	}
+	return anthropicMsgs
+}
+
+func (p *AnthropicProvider) newRequest(ctx context.Context, body []byte) (*http.Request, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL()+"/messages", bytes.NewBuffer(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", p.cfg.APIKey)
+	httpReq.Header.Set("anthropic-version", "2023-06-01")
+	return httpReq, nil
+}
+
+func (p *AnthropicProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	body, err := json.Marshal(anthropicMessagesRequest{
+		Model:     p.model(req),
+		MaxTokens: p.maxTokens(req),
+		Messages:  anthropicMessages(req),
+		Stream:    false,
+	})
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+
+	httpReq, err := p.newRequest(ctx, body)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return CompletionResponse{}, fmt.Errorf("anthropic error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return CompletionResponse{}, fmt.Errorf("anthropic error: %s", respBody)
+	}
+
+	var anthropicResp anthropicMessagesResponse
+	if err := json.Unmarshal(respBody, &anthropicResp); err != nil {
+		return CompletionResponse{}, fmt.Errorf("failed to parse anthropic response: %w", err)
+	}
+
+	text := ""
+	if len(anthropicResp.Content) > 0 {
+		text = anthropicResp.Content[0].Text
+	}
+
+	return CompletionResponse{
+		Choices: []Choice{{Text: text}},
+		Usage:   Usage{CompletionTokens: anthropicResp.Usage.OutputTokens},
+	}, nil
+}
+
+func (p *AnthropicProvider) Stream(ctx context.Context, req CompletionRequest, chunks chan<- Chunk) error {
+	defer close(chunks)
+
+	body, err := json.Marshal(anthropicMessagesRequest{
+		Model:     p.model(req),
+		MaxTokens: p.maxTokens(req),
+		Messages:  anthropicMessages(req),
+		Stream:    true,
+	})
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := p.newRequest(ctx, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("anthropic error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+scanLoop:
+	for scanner.Scan() {
+		payload, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+
+		var event anthropicStreamEvent
+		if err := json.Unmarshal([]byte(payload), &event); err != nil {
+			continue
+		}
+
+		switch event.Type {
+		case "content_block_delta":
+			chunks <- Chunk{Text: event.Delta.Text}
+		case "message_stop":
+			chunks <- Chunk{Done: true}
+			break scanLoop
+		}
+	}
+
+	return scanner.Err()
+}
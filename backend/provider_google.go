@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Gemini's generateContent request/response shapes (only the fields this
+// proxy needs)
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiGenerateRequest struct {
+	Contents []geminiContent `json:"contents"`
+}
+
+type geminiGenerateResponse struct {
+	Candidates []struct {
+		Content geminiContent `json:"content"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+type GoogleProvider struct {
+	cfg ProviderConfig
+}
+
+func (p *GoogleProvider) baseURL() string {
+	if p.cfg.BaseURL != "" {
+		return p.cfg.BaseURL
+	}
+	return "https://generativelanguage.googleapis.com/v1beta"
+}
+
+func (p *GoogleProvider) model(req CompletionRequest) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	return p.cfg.DefaultModel
+}
+
+// geminiContents converts completionMessages(req) into Gemini's content
+// shape. Gemini calls the assistant's role "model" rather than "assistant",
+// so that one role is translated; everything else (user, system) is passed
+// through as-is.
+func geminiContents(req CompletionRequest) []geminiContent {
+	messages := completionMessages(req)
+	contents := make([]geminiContent, len(messages))
+	for i, m := range messages {
+		role := m.Role
+		if role == "assistant" {
+			role = "model"
+		}
+		contents[i] = geminiContent{Role: role, Parts: []geminiPart{{Text: m.Content}}}
+	}
+	return contents
+}
+
+func (p *GoogleProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	body, err := json.Marshal(geminiGenerateRequest{
+		Contents: geminiContents(req),
+	})
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+
+	url := fmt.Sprintf("%s/models/%s:generateContent?key=%s", p.baseURL(), p.model(req), p.cfg.APIKey)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(body))
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return CompletionResponse{}, fmt.Errorf("google error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return CompletionResponse{}, fmt.Errorf("google error: %s", respBody)
+	}
+
+	var geminiResp geminiGenerateResponse
+	if err := json.Unmarshal(respBody, &geminiResp); err != nil {
+		return CompletionResponse{}, fmt.Errorf("failed to parse google response: %w", err)
+	}
+
+	text := ""
+	if len(geminiResp.Candidates) > 0 && len(geminiResp.Candidates[0].Content.Parts) > 0 {
+		text = geminiResp.Candidates[0].Content.Parts[0].Text
+	}
+
+	return CompletionResponse{
+		Choices: []Choice{{Text: text}},
+		Usage:   Usage{CompletionTokens: geminiResp.UsageMetadata.CandidatesTokenCount},
+	}, nil
+}
+
+// Gemini's REST API streams a JSON array over the wire rather than SSE or
+// newline-delimited chunks, so true incremental streaming isn't wired up here -
+// callers get the full response as a single chunk.
+func (p *GoogleProvider) Stream(ctx context.Context, req CompletionRequest, chunks chan<- Chunk) error {
+	defer close(chunks)
+
+	resp, err := p.Complete(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(resp.Choices) > 0 {
+		chunks <- Chunk{Text: resp.Choices[0].Text}
+	}
+	chunks <- Chunk{Done: true}
+	return nil
+}
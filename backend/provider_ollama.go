@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Talks to a local Ollama instance - the original, still-default backend
+type OllamaProvider struct {
+	cfg ProviderConfig
+}
+
+func (p *OllamaProvider) baseURL() string {
+	if p.cfg.BaseURL != "" {
+		return p.cfg.BaseURL
+	}
+	return "http://localhost:11434"
+}
+
+func (p *OllamaProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	// A multi-turn request (from /v1/chat/completions) goes to Ollama's
+	// /api/chat so the model sees the prior turns; a plain prompt keeps
+	// using /api/generate as before
+	if len(req.Messages) > 0 {
+		return p.completeChat(ctx, req)
+	}
+
+	ollamaReq := OllamaRequest{
+		Model:   req.Model,
+		Prompt:  req.Prompt,
+		Stream:  false,
+		Options: samplingOptions(req),
+	}
+
+	body, err := json.Marshal(ollamaReq)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL()+"/api/generate", bytes.NewBuffer(body))
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return CompletionResponse{}, fmt.Errorf("ollama error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return CompletionResponse{}, fmt.Errorf("ollama error: %s", respBody)
+	}
+
+	var ollamaResp OllamaResponse
+	if err := json.Unmarshal(respBody, &ollamaResp); err != nil {
+		return CompletionResponse{}, fmt.Errorf("failed to parse ollama response: %w", err)
+	}
+
+	return CompletionResponse{
+		Choices: []Choice{{Text: ollamaResp.Response}},
+		Usage:   ollamaUsage(ollamaResp),
+	}, nil
+}
+
+func (p *OllamaProvider) completeChat(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	ollamaReq := OllamaChatRequest{
+		Model:    req.Model,
+		Messages: req.Messages,
+		Stream:   false,
+	}
+
+	body, err := json.Marshal(ollamaReq)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL()+"/api/chat", bytes.NewBuffer(body))
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return CompletionResponse{}, fmt.Errorf("ollama error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return CompletionResponse{}, fmt.Errorf("ollama error: %s", respBody)
+	}
+
+	var ollamaResp OllamaChatResponse
+	if err := json.Unmarshal(respBody, &ollamaResp); err != nil {
+		return CompletionResponse{}, fmt.Errorf("failed to parse ollama response: %w", err)
+	}
+
+	return CompletionResponse{
+		Choices: []Choice{{Text: ollamaResp.Message.Content}},
+		Usage:   ollamaChatUsage(ollamaResp),
+	}, nil
+}
+
+func (p *OllamaProvider) Stream(ctx context.Context, req CompletionRequest, chunks chan<- Chunk) error {
+	if len(req.Messages) > 0 {
+		return p.streamChat(ctx, req, chunks)
+	}
+
+	defer close(chunks)
+
+	ollamaReq := OllamaRequest{
+		Model:   req.Model,
+		Prompt:  req.Prompt,
+		Stream:  true,
+		Options: samplingOptions(req),
+	}
+
+	body, err := json.Marshal(ollamaReq)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL()+"/api/generate", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("ollama error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ollama error: %s", respBody)
+	}
+
+	// Ollama writes one JSON object per line while streaming
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var line OllamaResponse
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+
+		if line.Done {
+			usage := ollamaUsage(line)
+			chunks <- Chunk{Done: true, Usage: &usage}
+			break
+		}
+		chunks <- Chunk{Text: line.Response}
+	}
+
+	return scanner.Err()
+}
+
+func (p *OllamaProvider) streamChat(ctx context.Context, req CompletionRequest, chunks chan<- Chunk) error {
+	defer close(chunks)
+
+	ollamaReq := OllamaChatRequest{
+		Model:    req.Model,
+		Messages: req.Messages,
+		Stream:   true,
+	}
+
+	body, err := json.Marshal(ollamaReq)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL()+"/api/chat", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("ollama error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("ollama error: %s", respBody)
+	}
+
+	// Same newline-delimited-JSON shape as /api/generate, just with Message
+	// instead of a flat Response string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var line OllamaChatResponse
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+
+		if line.Done {
+			usage := ollamaChatUsage(line)
+			chunks <- Chunk{Done: true, Usage: &usage}
+			break
+		}
+		chunks <- Chunk{Text: line.Message.Content}
+	}
+
+	return scanner.Err()
+}
+
+// Embed implements Embedder. Ollama's /api/embeddings only accepts one
+// prompt per call, so a batch input is turned into one Ollama call per string.
+func (p *OllamaProvider) Embed(ctx context.Context, model string, inputs []string) ([]EmbeddingData, Usage, error) {
+	data := make([]EmbeddingData, 0, len(inputs))
+	totalTokens := 0
+
+	for i, input := range inputs {
+		ollamaReq := OllamaEmbeddingsRequest{
+			Model:  model,
+			Prompt: input,
+		}
+
+		body, err := json.Marshal(ollamaReq)
+		if err != nil {
+			return nil, Usage{}, err
+		}
+
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL()+"/api/embeddings", bytes.NewBuffer(body))
+		if err != nil {
+			return nil, Usage{}, err
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		resp, err := httpClient.Do(httpReq)
+		if err != nil {
+			return nil, Usage{}, fmt.Errorf("ollama error: %w", err)
+		}
+
+		respBody, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, Usage{}, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, Usage{}, fmt.Errorf("ollama error: %s", respBody)
+		}
+
+		var ollamaResp OllamaEmbeddingsResponse
+		if err := json.Unmarshal(respBody, &ollamaResp); err != nil {
+			return nil, Usage{}, fmt.Errorf("failed to parse ollama response: %w", err)
+		}
+
+		data = append(data, EmbeddingData{Embedding: ollamaResp.Embedding, Index: i})
+		totalTokens += len(bytes.Fields([]byte(input)))
+	}
+
+	return data, Usage{PromptTokens: totalTokens, TotalTokens: totalTokens}, nil
+}
+
+// ollamaUsage turns Ollama's real token counts and durations into a Usage,
+// instead of estimating tokens by splitting the response on whitespace
+func ollamaUsage(resp OllamaResponse) Usage {
+	usage := Usage{
+		PromptTokens:     resp.PromptEvalCount,
+		CompletionTokens: resp.EvalCount,
+		TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+	}
+
+	if resp.EvalDuration > 0 {
+		usage.Timings = &Timings{
+			TokensPerSecond: float64(resp.EvalCount) / (float64(resp.EvalDuration) / 1e9),
+		}
+	}
+
+	return usage
+}
+
+// ollamaChatUsage mirrors ollamaUsage for /api/chat's response shape - real
+// token counts and timings instead of a whitespace estimate
+func ollamaChatUsage(resp OllamaChatResponse) Usage {
+	usage := Usage{
+		PromptTokens:     resp.PromptEvalCount,
+		CompletionTokens: resp.EvalCount,
+		TotalTokens:      resp.PromptEvalCount + resp.EvalCount,
+	}
+
+	if resp.EvalDuration > 0 {
+		usage.Timings = &Timings{
+			TokensPerSecond: float64(resp.EvalCount) / (float64(resp.EvalDuration) / 1e9),
+		}
+	}
+
+	return usage
+}
@@ -0,0 +1,78 @@
+package main
+
+import "testing"
+
+func TestOllamaUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		resp OllamaResponse
+		want Usage
+	}{
+		{
+			name: "zero eval duration leaves timings unset",
+			resp: OllamaResponse{PromptEvalCount: 10, EvalCount: 20, EvalDuration: 0},
+			want: Usage{PromptTokens: 10, CompletionTokens: 20, TotalTokens: 30},
+		},
+		{
+			name: "eval duration populates tokens per second",
+			resp: OllamaResponse{PromptEvalCount: 10, EvalCount: 20, EvalDuration: 2_000_000_000},
+			want: Usage{
+				PromptTokens:     10,
+				CompletionTokens: 20,
+				TotalTokens:      30,
+				Timings:          &Timings{TokensPerSecond: 10},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ollamaUsage(tt.resp)
+			assertUsageEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestOllamaChatUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		resp OllamaChatResponse
+		want Usage
+	}{
+		{
+			name: "zero eval duration leaves timings unset",
+			resp: OllamaChatResponse{PromptEvalCount: 5, EvalCount: 15, EvalDuration: 0},
+			want: Usage{PromptTokens: 5, CompletionTokens: 15, TotalTokens: 20},
+		},
+		{
+			name: "eval duration populates tokens per second",
+			resp: OllamaChatResponse{PromptEvalCount: 5, EvalCount: 15, EvalDuration: 1_500_000_000},
+			want: Usage{
+				PromptTokens:     5,
+				CompletionTokens: 15,
+				TotalTokens:      20,
+				Timings:          &Timings{TokensPerSecond: 10},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ollamaChatUsage(tt.resp)
+			assertUsageEqual(t, got, tt.want)
+		})
+	}
+}
+
+func assertUsageEqual(t *testing.T, got, want Usage) {
+	t.Helper()
+	if got.PromptTokens != want.PromptTokens || got.CompletionTokens != want.CompletionTokens || got.TotalTokens != want.TotalTokens {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if (got.Timings == nil) != (want.Timings == nil) {
+		t.Fatalf("got Timings = %v, want %v", got.Timings, want.Timings)
+	}
+	if got.Timings != nil && got.Timings.TokensPerSecond != want.Timings.TokensPerSecond {
+		t.Fatalf("got TokensPerSecond = %v, want %v", got.Timings.TokensPerSecond, want.Timings.TokensPerSecond)
+	}
+}
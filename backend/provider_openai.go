@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// OpenAI's chat completions request/response shapes (only the fields this
+// proxy needs)
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+	Stream   bool            `json:"stream"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
+}
+
+// Streamed chunk shape from OpenAI's chat/completions SSE endpoint
+type openAIChatStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+}
+
+type OpenAIProvider struct {
+	cfg ProviderConfig
+}
+
+func (p *OpenAIProvider) baseURL() string {
+	if p.cfg.BaseURL != "" {
+		return p.cfg.BaseURL
+	}
+	return "https://api.openai.com/v1"
+}
+
+func (p *OpenAIProvider) model(req CompletionRequest) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	return p.cfg.DefaultModel
+}
+
+// openAIMessages converts completionMessages(req) into OpenAI's message shape
+func openAIMessages(req CompletionRequest) []openAIMessage {
+	messages := completionMessages(req)
+	openaiMessages := make([]openAIMessage, len(messages))
+	for i, m := range messages {
+		openaiMessages[i] = openAIMessage{Role: m.Role, Content: m.Content}
+	}
+	return openaiMessages
+}
+
+func (p *OpenAIProvider) Complete(ctx context.Context, req CompletionRequest) (CompletionResponse, error) {
+	body, err := json.Marshal(openAIChatRequest{
+		Model:    p.model(req),
+		Messages: openAIMessages(req),
+		Stream:   false,
+	})
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL()+"/chat/completions", bytes.NewBuffer(body))
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.cfg.APIKey)
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return CompletionResponse{}, fmt.Errorf("openai error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return CompletionResponse{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return CompletionResponse{}, fmt.Errorf("openai error: %s", respBody)
+	}
+
+	var openaiResp openAIChatResponse
+	if err := json.Unmarshal(respBody, &openaiResp); err != nil {
+		return CompletionResponse{}, fmt.Errorf("failed to parse openai response: %w", err)
+	}
+	if len(openaiResp.Choices) == 0 {
+		return CompletionResponse{}, fmt.Errorf("openai returned no choices")
+	}
+
+	return CompletionResponse{
+		Choices: []Choice{{Text: openaiResp.Choices[0].Message.Content}},
+		Usage:   Usage{CompletionTokens: openaiResp.Usage.CompletionTokens},
+	}, nil
+}
+
+func (p *OpenAIProvider) Stream(ctx context.Context, req CompletionRequest, chunks chan<- Chunk) error {
+	defer close(chunks)
+
+	body, err := json.Marshal(openAIChatRequest{
+		Model:    p.model(req),
+		Messages: openAIMessages(req),
+		Stream:   true,
+	})
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL()+"/chat/completions", bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.cfg.APIKey)
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("openai error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// OpenAI streams "data: {...}" lines, terminated by a literal "data: [DONE]"
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		payload, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		if payload == "[DONE]" {
+			chunks <- Chunk{Done: true}
+			break
+		}
+
+		var streamChunk openAIChatStreamChunk
+		if err := json.Unmarshal([]byte(payload), &streamChunk); err != nil {
+			continue
+		}
+		if len(streamChunk.Choices) > 0 {
+			chunks <- Chunk{Text: streamChunk.Choices[0].Delta.Content}
+		}
+	}
+
+	return scanner.Err()
+}